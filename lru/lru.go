@@ -2,23 +2,59 @@ package lru
 
 import (
 	"container/list"
+	"sync/atomic"
+	"time"
 )
 
 // 缓存淘汰策略LRU: 最近最少使用，当缓存超过设定的最大值时，会移除最近最少使用的记录
 // LRU 算法的实现非常简单，维护一个队列，如果某条记录被访问了，则移动到队尾，那么队首则是最近最少访问的数据，淘汰该条记录即可。
+//
+// Cache 本身只负责字节统计、OnEvicted 回调以及 key/value 的实际存储，
+// "淘汰哪一个 key" 的决策被抽象成了 Policy 接口（见 policy.go），默认使用 LRUPolicy，
+// 可以通过 WithPolicy 换成 LFUPolicy 等其它策略。
+//
+// 此外，Cache 还支持 LRU-K 准入策略：当 k > 1 时，一个 key 不会直接进入主缓存，
+// 而是先在历史队列（history）中累计访问次数，只有命中次数达到 k 次后才会被"提升"进入主缓存，
+// 这样可以避免偶发的、一次性的大量不同 key 把真正的热点数据挤出缓存。
+//
+// Cache 还支持通过 AddWithTTL 为单条记录设置过期时间，具体见 ttl.go。
+//
+// Cache 还通过 Stats 暴露命中率、淘汰次数等统计信息，具体见 stats.go。
 
 type Cache struct {
-	maxBytes  int64                         // 允许使用的最大内存
-	nbytes    int64                         // 当前已使用的内存
-	ll        *list.List                    // 双向链表,go自带的双向链表
-	cache     map[string]*list.Element      // key为string,value为双向链表中对应节点的指针
-	OnEvicted func(key string, value Value) // 可选，当有记录被移除时的回调函数
+	maxBytes  int64             // 允许使用的最大内存
+	nbytes    int64             // 当前已使用的内存
+	items     map[string]*entry // key 到实际存储的 entry 的映射
+	policy    Policy            // 淘汰策略，决定 RemoveOldest 时淘汰哪个 key
+	OnEvicted OnEvictedFunc     // 可选，当有记录被移除时的回调函数，reason 说明移除原因
+
+	k               int                      // LRU-K 的 k 值，k<=1 时退化为普通 LRU，表示访问1次即进入主缓存
+	historyMaxBytes int64                    // 历史队列允许使用的最大内存
+	historyBytes    int64                    // 历史队列当前已使用的内存
+	historyList     *list.List               // 历史队列，记录尚未满 k 次访问的 key
+	historyCache    map[string]*list.Element // key 到历史队列节点的映射
+
+	janitor *janitor // 可选，由 Get/Add 顺带触发的惰性主动过期，见 ttl.go
+
+	hits      int64         // 命中次数，原子计数，见 stats.go
+	misses    int64         // 未命中次数，原子计数，见 stats.go
+	evictions int64         // 因容量超限被淘汰的次数，原子计数，见 stats.go
+	statsSink func(s Stats) // 可选，每次有记录被移除后回调一次，便于上报到 Prometheus 等
 }
 
-// 双向链表节点的数据类型，在链表中仍保存每个值对应的 key 的好处在于，淘汰队首节点时，需要用 key 从map中删除对应的映射
+// entry 保存 key/value 的实际数据，淘汰顺序由 Policy 单独维护
+// expireAt 为零值表示这条记录没有设置 TTL，永不过期
 type entry struct {
-	key   string
-	value Value
+	key      string
+	value    Value
+	expireAt time.Time
+}
+
+// 历史队列节点的数据类型，在 entry 的基础上额外记录访问次数，用于判断是否达到了 k 次访问
+type historyEntry struct {
+	key      string
+	value    Value
+	hitCount int
 }
 
 // 使用Len()来计算它占用了多少字节,只要实现Len()接口的方法都属于Value类型
@@ -26,77 +62,235 @@ type Value interface {
 	Len() int
 }
 
+// Option 用于在构造 Cache 时传入可选配置
+type Option func(c *Cache)
+
+// WithPolicy 指定 Cache 使用的淘汰策略，不传则默认为 LRUPolicy
+func WithPolicy(p Policy) Option {
+	return func(c *Cache) {
+		c.policy = p
+	}
+}
+
 // 实现len方法
 func (c *Cache) Len() int {
-	return c.ll.Len()
+	return len(c.items)
+}
+
+// 实例化Cache，k 默认为 1，即访问一次就进入主缓存（等价于普通 LRU）
+// 默认使用 LRUPolicy，可以通过 WithPolicy 传入 LFUPolicy 等其它策略
+// onEvicted 沿用旧版只关心 key/value 的签名；如果需要知道移除原因（淘汰/过期/删除），
+// 创建后直接对 c.OnEvicted 赋值一个 OnEvictedFunc 即可
+func New(maxBytes int64, onEvicted func(key string, value Value), opts ...Option) *Cache {
+	return newCache(maxBytes, 0, 1, onEvicted, opts...)
 }
 
-// 实例化Cache
-func New(maxBytes int64, onEvicted func(key string, value Value)) *Cache {
-	return &Cache{
-		maxBytes:  maxBytes,
-		ll:        list.New(),
-		cache:     make(map[string]*list.Element),
-		OnEvicted: onEvicted,
+// NewWithK 实例化一个支持 LRU-K 准入策略的 Cache
+// maxBytes: 主缓存允许使用的最大内存
+// historyMaxBytes: 历史队列允许使用的最大内存，为 0 表示不限制
+// k: 一个 key 需要被访问的次数才会被提升进入主缓存，k<=1 时退化为普通 LRU
+func NewWithK(maxBytes, historyMaxBytes int64, k int, onEvicted func(key string, value Value), opts ...Option) *Cache {
+	return newCache(maxBytes, historyMaxBytes, k, onEvicted, opts...)
+}
+
+func newCache(maxBytes, historyMaxBytes int64, k int, onEvicted func(key string, value Value), opts ...Option) *Cache {
+	if k < 1 {
+		k = 1
+	}
+	c := &Cache{
+		maxBytes:        maxBytes,
+		items:           make(map[string]*entry),
+		policy:          NewLRUPolicy(),
+		OnEvicted:       LegacyOnEvicted(onEvicted),
+		k:               k,
+		historyMaxBytes: historyMaxBytes,
+		historyList:     list.New(),
+		historyCache:    make(map[string]*list.Element),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// 查找功能:因为被访问了，所以移动到队尾
-// 1. 从字典中找到对应的双向链表的节点
-// 2. 将该节点移动到队尾
+// 查找功能:因为被访问了，所以通知 policy 这是一次命中
+// 如果 key 只存在于历史队列中（还未达到 k 次访问），对调用方而言仍视为未命中，
+// 内部只把它在历史队列中移到队首保鲜；这次 Get 不计入访问次数，也不会触发提升，
+// 只有 Add 的写入才计数。
 func (c *Cache) Get(key string) (value Value, ok bool) {
-	// 从字典中找到对应的双向链表的节点
-	if ele, ok := c.cache[key]; ok {
-		// 将该节点移动到队尾,双向链表作为队列，队首队尾是相对的，在这里约定 front 为队尾
-		c.ll.MoveToFront(ele)
-		kv := ele.Value.(*entry)
-		return kv.value, true
+	c.maybeSweep()
+	if e, ok := c.items[key]; ok {
+		if e.expired() {
+			c.policy.Remove(key)
+			c.removeEntry(key, e, ReasonExpired)
+			atomic.AddInt64(&c.misses, 1)
+			return nil, false
+		}
+		c.policy.Touch(key)
+		atomic.AddInt64(&c.hits, 1)
+		return e.value, true
+	}
+	// 主缓存未命中，检查历史队列
+	if c.k > 1 {
+		c.touchHistory(key, nil)
 	}
+	atomic.AddInt64(&c.misses, 1)
 	return
 }
 
-// 删除
-func (c *Cache) RemoveOldest() {
-	// 取到队首节点(最近最少访问的节点)，从链表中删除
-	ele := c.ll.Back() // 取到队首节点
-	if ele != nil {
-		// 从链表中删除该节点
-		c.ll.Remove(ele)
-		// 取到存储的真实值,key-value
-		kv := ele.Value.(*entry)
-		// 从字典中 c.cache 删除该节点的映射关系，根据map中的key删除
-		delete(c.cache, kv.key)
-		// 更新当前所用的内存 c.nbytes
-		c.nbytes -= int64(len(kv.key)) + int64(kv.value.Len())
-		// 如果回调函数 OnEvicted 不为 nil，则调用回调函数
-		if c.OnEvicted != nil {
-			c.OnEvicted(kv.key, kv.value)
+// touchHistory 在历史队列中记录一次 key 的访问，如果 value 非 nil 表示这是一次新增/写入。
+// 只有写入（Add）才计入命中次数；单纯的 Get 只把节点移到队首保鲜，不增加计数，
+// 否则一次 Add 加一次 Get 就会被算成两次访问，导致 k 次真实写入还没发生就被提前提升。
+// 命中次数达到 k 后会把该 key 从历史队列提升进主缓存。
+func (c *Cache) touchHistory(key string, value Value) {
+	if ele, ok := c.historyCache[key]; ok {
+		c.historyList.MoveToFront(ele)
+		he := ele.Value.(*historyEntry)
+		if value != nil {
+			c.historyBytes += int64(value.Len()) - int64(he.value.Len())
+			he.value = value
+			he.hitCount++
 		}
+		if he.hitCount >= c.k && he.value != nil {
+			// 达到 k 次访问，提升进主缓存
+			c.historyList.Remove(ele)
+			delete(c.historyCache, key)
+			c.historyBytes -= int64(len(key)) + int64(he.value.Len())
+			c.addToMain(key, he.value, time.Time{})
+		}
+		return
+	}
+	if value == nil {
+		// 历史队列中没有记录，且这次只是一次读 miss，不产生写入，无法新建历史记录
+		return
+	}
+	ele := c.historyList.PushFront(&historyEntry{key: key, value: value, hitCount: 1})
+	c.historyCache[key] = ele
+	c.historyBytes += int64(len(key)) + int64(value.Len())
+	if c.k <= 1 {
+		// k<=1 时一次访问即提升
+		c.historyList.Remove(ele)
+		delete(c.historyCache, key)
+		c.historyBytes -= int64(len(key)) + int64(value.Len())
+		c.addToMain(key, value, time.Time{})
+		return
+	}
+	for c.historyMaxBytes != 0 && c.historyMaxBytes < c.historyBytes {
+		c.removeOldestHistory()
 	}
 }
 
-// 新增/修改
-// 需要注意的是，新增或修改可能达到内存的最大限制，从而触发删除逻辑
-func (c *Cache) Add(key string, value Value) {
-	// 如果键存在，则更新（修改）对应节点的值，并将该节点移到队尾
-	if ele, ok := c.cache[key]; ok {
-		// 存在则更新对应节点的值，并将该节点移到队尾,因为被访问了
-		c.ll.MoveToFront(ele)
-		// 更新值
-		kv := ele.Value.(*entry)
-		// 更新 c.nbytes，用传入的value的长度减去原来的长度，计算出使用的内存大小
-		c.nbytes += int64(value.Len()) - int64(kv.value.Len())
-		kv.value = value
+// removeOldestHistory 淘汰历史队列队首（最近最少访问）的记录
+func (c *Cache) removeOldestHistory() {
+	ele := c.historyList.Back()
+	if ele == nil {
+		return
+	}
+	c.historyList.Remove(ele)
+	he := ele.Value.(*historyEntry)
+	delete(c.historyCache, he.key)
+	c.historyBytes -= int64(len(he.key)) + int64(he.value.Len())
+}
+
+// addToMain 把一个 key/value 写入主缓存，复用与 Add 相同的写入与淘汰逻辑
+// expireAt 为零值表示不设置 TTL，由 AddWithTTL 传入非零值
+func (c *Cache) addToMain(key string, value Value, expireAt time.Time) {
+	if e, ok := c.items[key]; ok {
+		c.policy.Touch(key)
+		c.nbytes += int64(value.Len()) - int64(e.value.Len())
+		e.value = value
+		e.expireAt = expireAt
 	} else {
-		// 不存在则是新增场景，首先队尾添加新节点 &entry{key, value}
-		ele := c.ll.PushFront(&entry{key, value})
-		c.cache[key] = ele
-		// 更新 c.nbytes，新增key+value的长度
+		c.items[key] = &entry{key: key, value: value, expireAt: expireAt}
+		c.policy.Add(key, value.Len())
 		c.nbytes += int64(len(key)) + int64(value.Len())
 	}
-	// 更新 c.nbytes，如果超过了设定的最大值 c.maxBytes，则循环移除最少访问的节点
-	// 使用循环，是因为添加了一个很大的键值队，移除一次可能还不够，需要多次移除
 	for c.maxBytes != 0 && c.maxBytes < c.nbytes {
 		c.RemoveOldest()
 	}
 }
+
+// removeEntry 把一个已经从 policy 中摘除的 entry 从实际存储中删除，更新字节统计并触发 OnEvicted
+func (c *Cache) removeEntry(key string, e *entry, reason Reason) {
+	delete(c.items, key)
+	c.nbytes -= int64(len(key)) + int64(e.value.Len())
+	if reason == ReasonEvicted {
+		atomic.AddInt64(&c.evictions, 1)
+	}
+	if c.OnEvicted != nil {
+		c.OnEvicted(key, e.value, reason)
+	}
+	if c.statsSink != nil {
+		c.statsSink(c.Stats())
+	}
+}
+
+// 删除
+// 淘汰哪一个 key 由 policy.Evict() 决定，这里只负责从实际存储中摘除并更新字节统计
+func (c *Cache) RemoveOldest() {
+	key, ok := c.policy.Evict()
+	if !ok {
+		return
+	}
+	e, ok := c.items[key]
+	if !ok {
+		return
+	}
+	c.removeEntry(key, e, ReasonEvicted)
+}
+
+// 新增/修改
+// 需要注意的是，新增或修改可能达到内存的最大限制，从而触发删除逻辑
+// 当 k > 1 时，一个此前未进入过主缓存的 key 会先经过历史队列的累计访问计数，
+// 只有达到 k 次后才会真正写入主缓存。
+func (c *Cache) Add(key string, value Value) {
+	c.maybeSweep()
+	// 如果键已经在主缓存中，则更新（修改）对应节点的值
+	if _, ok := c.items[key]; ok {
+		c.addToMain(key, value, time.Time{})
+		return
+	}
+	// 主缓存中不存在，且开启了 LRU-K，则先经过历史队列
+	if c.k > 1 {
+		c.touchHistory(key, value)
+		return
+	}
+	// 普通 LRU（k<=1）直接写入主缓存
+	c.addToMain(key, value, time.Time{})
+}
+
+// Remove 显式删除一个 key，除了 OnEvicted 和 statsSink 回调之外不会触发其它副作用
+func (c *Cache) Remove(key string) {
+	if e, ok := c.items[key]; ok {
+		c.policy.Remove(key)
+		c.removeEntry(key, e, ReasonRemoved)
+		return
+	}
+	c.removeFromHistory(key)
+}
+
+// removeFromHistory 把一个 key 尚未提升的历史队列记录清理掉，不涉及主缓存。
+// 用于 Remove 删除一个只在历史队列中的 key，以及 AddWithTTL 让 key 绕过历史队列、
+// 直接写入主缓存之前，避免同一个 key 同时出现在 items 和 historyCache 里，导致 historyBytes 永久多算。
+func (c *Cache) removeFromHistory(key string) {
+	if c.k <= 1 {
+		return
+	}
+	if ele, ok := c.historyCache[key]; ok {
+		he := ele.Value.(*historyEntry)
+		c.historyList.Remove(ele)
+		delete(c.historyCache, key)
+		c.historyBytes -= int64(len(key)) + int64(he.value.Len())
+	}
+}
+
+// Purge 清空 Cache 中的所有数据，包括历史队列
+func (c *Cache) Purge() {
+	for key, e := range c.items {
+		c.policy.Remove(key)
+		c.removeEntry(key, e, ReasonRemoved)
+	}
+	c.historyList = list.New()
+	c.historyCache = make(map[string]*list.Element)
+	c.historyBytes = 0
+}
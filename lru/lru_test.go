@@ -1,6 +1,7 @@
 package lru
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 )
@@ -61,6 +62,107 @@ func TestOnEvicted(t *testing.T) {
 	}
 }
 
+// 测试 LRU-K：一次性访问大量不同的 key（只访问1次）不应该挤掉已经被访问了 K 次的热点 key
+func TestLRUK_HotKeySurvivesFlood(t *testing.T) {
+	k := 2
+	hot := "hotKey"
+	// 热点 key 访问两次，达到 k=2，提升进主缓存
+	lru := NewWithK(int64(len(hot)+5)*3, 1<<20, k, nil)
+	lru.Add(hot, String("1"))
+	if _, ok := lru.Get(hot); ok {
+		t.Fatalf("first access should still be a history-only miss")
+	}
+	lru.Add(hot, String("1"))
+	if v, ok := lru.Get(hot); !ok || string(v.(String)) != "1" {
+		t.Fatalf("hotKey should be promoted to main cache after %d accesses", k)
+	}
+
+	// 大量只访问一次的不同 key 不应该能够进入主缓存，也不应该影响 hotKey
+	for i := 0; i < 100; i++ {
+		lru.Add(fmt.Sprintf("flood%d", i), String("x"))
+	}
+	if _, ok := lru.Get(hot); !ok {
+		t.Fatalf("hotKey should not be evicted by a flood of one-shot keys")
+	}
+}
+
+// 测试 LRU-K：未达到 k 次访问的 key 对调用方而言始终是 miss
+func TestLRUK_BelowThresholdIsMiss(t *testing.T) {
+	lru := NewWithK(1<<20, 1<<20, 3, nil)
+	lru.Add("key1", String("v1"))
+	if _, ok := lru.Get("key1"); ok {
+		t.Fatalf("key1 should still miss, only accessed once (k=3)")
+	}
+	lru.Add("key1", String("v1"))
+	if _, ok := lru.Get("key1"); ok {
+		t.Fatalf("key1 should still miss, only accessed twice (k=3)")
+	}
+}
+
+// 测试历史队列自己的字节预算：historyMaxBytes 超限时会淘汰历史队列里最久未被触碰、
+// 尚未提升进主缓存的 key
+func TestLRUK_HistoryMaxBytesEviction(t *testing.T) {
+	const historyMaxBytes = 4 // 只够容纳一条历史记录（key+value各占若干字节）
+	lru := NewWithK(1<<20, historyMaxBytes, 3, nil)
+
+	lru.Add("old", String("v")) // len("old")+len("v") = 4，刚好占满历史队列的预算
+	if _, ok := lru.historyCache["old"]; !ok {
+		t.Fatalf("old should be sitting in the history queue")
+	}
+
+	lru.Add("new", String("v")) // 超出 historyMaxBytes，应该把 old 挤出历史队列
+
+	if _, ok := lru.historyCache["old"]; ok {
+		t.Fatalf("old should have been evicted from the history queue once historyMaxBytes is exceeded")
+	}
+	if _, ok := lru.historyCache["new"]; !ok {
+		t.Fatalf("new should still be sitting in the history queue")
+	}
+}
+
+// 测试 LFU 策略：访问频次更高的 key 应该在容量不足时被保留下来
+func TestCache_LFUPolicy(t *testing.T) {
+	k1, k2, k3 := "key1", "key2", "key3"
+	cap := int64(len(k1+"value1") + len(k2+"value2"))
+	lru := New(cap, nil, WithPolicy(NewLFUPolicy()))
+	lru.Add(k1, String("value1"))
+	lru.Add(k2, String("value2"))
+	// k1 被多次访问，频次更高，理应在淘汰时被保留
+	lru.Get(k1)
+	lru.Get(k1)
+
+	lru.Add(k3, String("value3"))
+
+	if _, ok := lru.Get(k1); !ok {
+		t.Fatalf("key1 has higher frequency, should not be evicted")
+	}
+	if _, ok := lru.Get(k2); ok {
+		t.Fatalf("key2 has lower frequency, should have been evicted")
+	}
+}
+
+// 测试 LFU 策略下 Cache.Remove 删除掉某个频率桶里唯一的 key 后，
+// 该桶会被正确摘除，不会破坏后续 Evict() 的淘汰顺序
+func TestCache_LFURemove(t *testing.T) {
+	evicted := ""
+	callback := func(key string, value Value) { evicted = key }
+	lru := New(int64(100), callback, WithPolicy(NewLFUPolicy()))
+	lru.Add("a", String("1"))
+	lru.Add("b", String("1"))
+	lru.Add("c", String("1"))
+	lru.Get("b") // b 频次更高，单独进入一个新的频率桶
+
+	lru.Remove("b") // b 是它所在桶里唯一的 key，删除后这个桶应该被正确摘除
+
+	lru.RemoveOldest() // 触发一次按频率淘汰，验证 b 的桶被摘除后没有破坏剩余的频率链表
+	if evicted != "a" {
+		t.Fatalf("expected lowest-frequency key 'a' to be evicted, got %q", evicted)
+	}
+	if _, ok := lru.Get("c"); !ok {
+		t.Fatalf("'c' should still be present")
+	}
+}
+
 func TestMapDelete(t *testing.T) {
 	m := make(map[string]string, 10)
 	m["key1"] = "value1"
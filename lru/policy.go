@@ -0,0 +1,183 @@
+package lru
+
+import "container/list"
+
+// Policy 是 Cache 的淘汰策略接口，Cache 只负责字节统计、OnEvicted 回调以及
+// key/value 的实际存储，具体"淘汰哪一个 key"的决策交给 Policy 实现，
+// 这样 Cache 就可以在 LRU、LFU 等不同策略之间切换而不用改动自身的逻辑。
+type Policy interface {
+	// Touch 记录一次对 key 的访问（命中）
+	Touch(key string)
+	// Add 记录一个新 key 的加入，size 为该 key 占用的字节数，部分策略会用到
+	Add(key string, size int)
+	// Remove 从策略中移除一个 key，例如被显式删除或被主缓存覆盖前的清理
+	Remove(key string)
+	// Evict 选出一个应当被淘汰的 key；策略中没有任何 key 时 ok 为 false
+	Evict() (key string, ok bool)
+}
+
+// LRUPolicy 是最近最少使用策略：双向链表维护访问顺序，队首为最近最少访问的 key
+type LRUPolicy struct {
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// NewLRUPolicy 实例化一个 LRUPolicy
+func NewLRUPolicy() *LRUPolicy {
+	return &LRUPolicy{
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Touch 将 key 对应的节点移动到队尾
+func (p *LRUPolicy) Touch(key string) {
+	if ele, ok := p.items[key]; ok {
+		p.ll.MoveToFront(ele)
+	}
+}
+
+// Add 新增一个 key，如果 key 已存在则等价于一次 Touch
+func (p *LRUPolicy) Add(key string, size int) {
+	if ele, ok := p.items[key]; ok {
+		p.ll.MoveToFront(ele)
+		return
+	}
+	p.items[key] = p.ll.PushFront(key)
+}
+
+// Remove 从策略中移除一个 key
+func (p *LRUPolicy) Remove(key string) {
+	if ele, ok := p.items[key]; ok {
+		p.ll.Remove(ele)
+		delete(p.items, key)
+	}
+}
+
+// Evict 弹出队首（最近最少访问）的 key
+func (p *LRUPolicy) Evict() (key string, ok bool) {
+	ele := p.ll.Back()
+	if ele == nil {
+		return "", false
+	}
+	key = ele.Value.(string)
+	p.ll.Remove(ele)
+	delete(p.items, key)
+	return key, true
+}
+
+// freqBucket 是频率桶：同一访问频次的所有 key 组成一个双向链表节点，
+// 桶本身也通过 prev/next 组成一条按频次递增排列的双向链表
+type freqBucket struct {
+	freq       int
+	items      *list.List
+	prev, next *freqBucket
+}
+
+// LFUPolicy 是最不经常使用策略，采用经典的 O(1) 实现：
+// 频率桶组成的双向链表 + map[string]*list.Element 做 key 到桶内节点的 O(1) 定位
+// + map[string]*freqBucket 做 key 到所在频率桶的反向定位
+type LFUPolicy struct {
+	head    *freqBucket // 频率最小的桶
+	items   map[string]*list.Element
+	buckets map[string]*freqBucket
+}
+
+// NewLFUPolicy 实例化一个 LFUPolicy
+func NewLFUPolicy() *LFUPolicy {
+	return &LFUPolicy{
+		items:   make(map[string]*list.Element),
+		buckets: make(map[string]*freqBucket),
+	}
+}
+
+// Touch 把 key 从当前频率桶移动到 freq+1 对应的桶（不存在则新建），
+// 如果原来的桶因此变空，则从频率链表上摘除
+func (p *LFUPolicy) Touch(key string) {
+	b, ok := p.buckets[key]
+	if !ok {
+		return
+	}
+	ele := p.items[key]
+	b.items.Remove(ele)
+
+	nextFreq := b.freq + 1
+	var target *freqBucket
+	if b.next != nil && b.next.freq == nextFreq {
+		target = b.next
+	} else {
+		target = &freqBucket{freq: nextFreq, items: list.New(), prev: b, next: b.next}
+		if b.next != nil {
+			b.next.prev = target
+		}
+		b.next = target
+	}
+	nele := target.items.PushFront(key)
+	p.items[key] = nele
+	p.buckets[key] = target
+
+	if b.items.Len() == 0 {
+		p.unlinkBucket(b)
+	}
+}
+
+// Add 新增一个 key，初始频次为 1；如果 key 已存在则等价于一次 Touch
+func (p *LFUPolicy) Add(key string, size int) {
+	if _, ok := p.buckets[key]; ok {
+		p.Touch(key)
+		return
+	}
+	if p.head == nil || p.head.freq != 1 {
+		nb := &freqBucket{freq: 1, items: list.New(), next: p.head}
+		if p.head != nil {
+			p.head.prev = nb
+		}
+		p.head = nb
+	}
+	ele := p.head.items.PushFront(key)
+	p.items[key] = ele
+	p.buckets[key] = p.head
+}
+
+// Remove 从策略中移除一个 key
+func (p *LFUPolicy) Remove(key string) {
+	b, ok := p.buckets[key]
+	if !ok {
+		return
+	}
+	ele := p.items[key]
+	b.items.Remove(ele)
+	delete(p.items, key)
+	delete(p.buckets, key)
+	if b.items.Len() == 0 {
+		p.unlinkBucket(b)
+	}
+}
+
+// Evict 淘汰频率最低的桶中最久未被触碰的 key（桶内队尾）
+func (p *LFUPolicy) Evict() (key string, ok bool) {
+	if p.head == nil {
+		return "", false
+	}
+	ele := p.head.items.Back()
+	key = ele.Value.(string)
+	p.head.items.Remove(ele)
+	delete(p.items, key)
+	delete(p.buckets, key)
+	if p.head.items.Len() == 0 {
+		p.unlinkBucket(p.head)
+	}
+	return key, true
+}
+
+// unlinkBucket 把一个已经空了的频率桶从频率链表上摘除
+func (p *LFUPolicy) unlinkBucket(b *freqBucket) {
+	if b.prev != nil {
+		b.prev.next = b.next
+	} else {
+		p.head = b.next
+	}
+	if b.next != nil {
+		b.next.prev = b.prev
+	}
+}
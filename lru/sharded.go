@@ -0,0 +1,110 @@
+package lru
+
+import (
+	"hash/fnv"
+	"sync"
+)
+
+// Cache 本身不是并发安全的，但 GeeCache 是一个分布式缓存，
+// 并发的 Get/Add 是常态。ShardedCache 把 key 通过 FNV-1a 哈希分散到多个
+// 内部的 *Cache 分片上，每个分片有自己独立的锁，从而把锁粒度从"整个缓存"
+// 降到"单个分片"，减少并发场景下的锁竞争。
+type ShardedCache struct {
+	shards []*cacheShard
+}
+
+// cacheShard 是 ShardedCache 的一个分片，持有一把独立的锁和一个普通的 *Cache
+type cacheShard struct {
+	mu    sync.RWMutex
+	cache *Cache
+}
+
+// NewSharded 实例化一个 ShardedCache，maxBytes 会被（向上取整地）平均分配到每个分片上，
+// onEvicted 和 opts（例如 WithPolicy、WithJanitor）都会被透传给每一个分片的 Cache。
+// maxBytes == 0 表示不限制，会原样透传给每个分片；maxBytes > 0 时必须用向上取整而不是
+// 普通整除，否则 shards 数量大于 maxBytes 时，整除会截断成 0——而 0 恰好是 Cache 里
+// "不限制容量" 的哨兵值，会导致分片意外变成不限容量。
+func NewSharded(shards int, maxBytes int64, onEvicted func(key string, value Value), opts ...Option) *ShardedCache {
+	if shards <= 0 {
+		shards = 1
+	}
+	var perShard int64
+	if maxBytes > 0 {
+		perShard = (maxBytes + int64(shards) - 1) / int64(shards)
+	}
+	cs := make([]*cacheShard, shards)
+	for i := range cs {
+		cs[i] = &cacheShard{cache: New(perShard, onEvicted, opts...)}
+	}
+	return &ShardedCache{shards: cs}
+}
+
+// shardFor 用 FNV-1a 哈希选出 key 所属的分片
+func (s *ShardedCache) shardFor(key string) *cacheShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// Get 查找 key 所在的分片并委托给它的 Cache.Get
+// 注意 LRU 的 Get 本身会调整访问顺序，属于写操作，因此这里加的是写锁
+func (s *ShardedCache) Get(key string) (value Value, ok bool) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.cache.Get(key)
+}
+
+// Add 查找 key 所在的分片并委托给它的 Cache.Add
+func (s *ShardedCache) Add(key string, value Value) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.cache.Add(key, value)
+}
+
+// Remove 查找 key 所在的分片并委托给它的 Cache.Remove
+func (s *ShardedCache) Remove(key string) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.cache.Remove(key)
+}
+
+// Len 返回所有分片中记录的总数
+func (s *ShardedCache) Len() int {
+	total := 0
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		total += sh.cache.Len()
+		sh.mu.RUnlock()
+	}
+	return total
+}
+
+// Purge 清空所有分片
+func (s *ShardedCache) Purge() {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		sh.cache.Purge()
+		sh.mu.Unlock()
+	}
+}
+
+// Stats 聚合所有分片的统计信息。每个分片都在自己的锁保护下读取，
+// 所以这里汇总出来的 BytesInUse/Entries 不会像裸 *Cache.Stats() 那样读到撕裂的值，
+// 只是各分片的快照时间点不完全一致，Stats() 返回的是各分片快照值的汇总，不是全局单一时刻的快照。
+func (s *ShardedCache) Stats() Stats {
+	var total Stats
+	for _, sh := range s.shards {
+		sh.mu.RLock()
+		st := sh.cache.Stats()
+		sh.mu.RUnlock()
+		total.Hits += st.Hits
+		total.Misses += st.Misses
+		total.Evictions += st.Evictions
+		total.BytesInUse += st.BytesInUse
+		total.Entries += st.Entries
+	}
+	return total
+}
@@ -0,0 +1,142 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 测试 ShardedCache 的基本读写和 Remove/Purge 行为
+func TestShardedCache_Basic(t *testing.T) {
+	sc := NewSharded(4, 1<<20, nil)
+	sc.Add("key1", String("value1"))
+	sc.Add("key2", String("value2"))
+
+	if v, ok := sc.Get("key1"); !ok || string(v.(String)) != "value1" {
+		t.Fatalf("ShardedCache Get key1 failed")
+	}
+	if sc.Len() != 2 {
+		t.Fatalf("ShardedCache Len expected 2, got %d", sc.Len())
+	}
+
+	sc.Remove("key1")
+	if _, ok := sc.Get("key1"); ok {
+		t.Fatalf("key1 should have been removed")
+	}
+
+	sc.Purge()
+	if sc.Len() != 0 {
+		t.Fatalf("ShardedCache Len expected 0 after Purge, got %d", sc.Len())
+	}
+}
+
+// 测试 ShardedCache.Stats 能在各分片自己的锁保护下汇总出准确的 Entries/BytesInUse
+func TestShardedCache_Stats(t *testing.T) {
+	sc := NewSharded(4, 1<<20, nil)
+	sc.Add("key1", String("value1"))
+	sc.Add("key2", String("value2"))
+	sc.Get("key1")
+	sc.Get("missing")
+
+	s := sc.Stats()
+	if s.Entries != 2 {
+		t.Fatalf("expected 2 entries, got %d", s.Entries)
+	}
+	if s.Hits != 1 || s.Misses != 1 {
+		t.Fatalf("expected 1 hit and 1 miss, got %+v", s)
+	}
+}
+
+// 测试 shards 数量大于 maxBytes 时，每个分片的容量会向上取整而不是被整除截断成 0，
+// 否则 0 恰好是 Cache 里"不限制容量"的哨兵值，分片会意外变成不限容量
+func TestShardedCache_SmallMaxBytesDoesNotBecomeUnbounded(t *testing.T) {
+	sc := NewSharded(4, 3, nil)
+	for i := 0; i < 1000; i++ {
+		sc.Add(fmt.Sprintf("key%d", i), String("v"))
+	}
+	if sc.Len() >= 1000 {
+		t.Fatalf("shards should have evicted under a tiny maxBytes, got Len()=%d", sc.Len())
+	}
+}
+
+// 测试 NewSharded 能把 opts（例如 WithJanitor）透传给每个分片的 Cache
+func TestShardedCache_WithJanitor(t *testing.T) {
+	sc := NewSharded(4, 1<<20, nil, WithJanitor(5*time.Millisecond))
+	for i, sh := range sc.shards {
+		if sh.cache.janitor == nil {
+			t.Fatalf("shard %d did not receive the WithJanitor option", i)
+		}
+	}
+	sc.Add("key1", String("value1"))
+	if _, ok := sc.Get("key1"); !ok {
+		t.Fatalf("ShardedCache with WithJanitor should still read back key1")
+	}
+}
+
+// singleMutexCache 用一把全局锁包裹一个普通 *Cache，作为对照组，
+// 用来和 ShardedCache 在混合读写场景下做吞吐量对比
+type singleMutexCache struct {
+	mu    sync.RWMutex
+	cache *Cache
+}
+
+func newSingleMutexCache(maxBytes int64) *singleMutexCache {
+	return &singleMutexCache{cache: New(maxBytes, nil)}
+}
+
+func (s *singleMutexCache) Get(key string) (Value, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cache.Get(key)
+}
+
+func (s *singleMutexCache) Add(key string, value Value) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache.Add(key, value)
+}
+
+// BenchmarkSingleMutexCache_MixedReadWrite 压测单把互斥锁包裹的 Cache
+func BenchmarkSingleMutexCache_MixedReadWrite(b *testing.B) {
+	c := newSingleMutexCache(1 << 24)
+	for i := 0; i < 1000; i++ {
+		c.Add(fmt.Sprintf("key%d", i), String("value"))
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := fmt.Sprintf("key%d", i%1000)
+			if i%10 == 0 {
+				c.Add(key, String("value"))
+			} else {
+				c.Get(key)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkShardedCache_MixedReadWrite 压测按 key 分片的 ShardedCache
+func BenchmarkShardedCache_MixedReadWrite(b *testing.B) {
+	c := NewSharded(16, 1<<24, nil)
+	for i := 0; i < 1000; i++ {
+		c.Add(fmt.Sprintf("key%d", i), String("value"))
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := fmt.Sprintf("key%d", i%1000)
+			if i%10 == 0 {
+				c.Add(key, String("value"))
+			} else {
+				c.Get(key)
+			}
+			i++
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package lru
+
+import "sync/atomic"
+
+// Stats 是 Cache 在某一时刻的统计快照，用于观测缓存的命中率和淘汰情况
+type Stats struct {
+	Hits       int64 // 命中次数
+	Misses     int64 // 未命中次数（包括 LRU-K 历史队列中尚未提升的访问）
+	Evictions  int64 // 因容量超限被 Policy 淘汰的次数，不包括 TTL 过期和显式 Remove
+	BytesInUse int64 // 当前主缓存占用的字节数
+	Entries    int64 // 当前主缓存的记录数
+}
+
+// Stats 返回当前的统计快照。Hits/Misses/Evictions 基于 atomic 计数器读取，
+// 并发调用这三个字段不会出错；但 BytesInUse/Entries 只是对 nbytes/items 的普通读取，
+// 和 Get/Add/RemoveOldest 之间没有同步，如果在并发场景下直接调用裸的 *Cache.Stats()，
+// 这两个字段可能读到不一致的瞬时值。通过 ShardedCache.Stats() 调用时，
+// 每个分片会在自己已有的那把锁保护下读取，BytesInUse/Entries 才是准确的。
+func (c *Cache) Stats() Stats {
+	return Stats{
+		Hits:       atomic.LoadInt64(&c.hits),
+		Misses:     atomic.LoadInt64(&c.misses),
+		Evictions:  atomic.LoadInt64(&c.evictions),
+		BytesInUse: c.nbytes,
+		Entries:    int64(len(c.items)),
+	}
+}
+
+// Reset 把 Hits/Misses/Evictions 计数器清零，不影响 BytesInUse/Entries 这类反映当前状态的字段
+func (c *Cache) Reset() {
+	atomic.StoreInt64(&c.hits, 0)
+	atomic.StoreInt64(&c.misses, 0)
+	atomic.StoreInt64(&c.evictions, 0)
+}
+
+// WithStatsSink 注册一个回调，每当有记录从 Cache 中被移除（淘汰/过期/显式删除）时，
+// 都会带上当时的统计快照调用一次，便于上层不依赖 client 库直接导出到 Prometheus 等系统
+func WithStatsSink(sink func(s Stats)) Option {
+	return func(c *Cache) {
+		c.statsSink = sink
+	}
+}
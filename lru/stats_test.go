@@ -0,0 +1,62 @@
+package lru
+
+import "testing"
+
+// 测试 Stats 对 Hits/Misses/Evictions/BytesInUse/Entries 的统计是否正确
+func TestCache_Stats(t *testing.T) {
+	k1, k2, k3 := "key1", "key2", "key3"
+	v1, v2, v3 := "value1", "value2", "value3"
+	cap := len(k1+v1) + len(k2+v2)
+	lru := New(int64(cap), nil)
+
+	lru.Add(k1, String(v1))
+	lru.Add(k2, String(v2))
+	lru.Get(k1)     // hit
+	lru.Get("miss") // miss
+	lru.Add(k3, String(v3))
+
+	s := lru.Stats()
+	if s.Hits != 1 {
+		t.Fatalf("expected 1 hit, got %d", s.Hits)
+	}
+	if s.Misses != 1 {
+		t.Fatalf("expected 1 miss, got %d", s.Misses)
+	}
+	if s.Evictions != 1 {
+		t.Fatalf("expected 1 eviction, got %d", s.Evictions)
+	}
+	if s.Entries != 2 {
+		t.Fatalf("expected 2 entries, got %d", s.Entries)
+	}
+}
+
+// 测试 Reset 只清零计数器，不影响 BytesInUse/Entries 这类反映当前状态的字段
+func TestCache_StatsReset(t *testing.T) {
+	lru := New(int64(1<<20), nil)
+	lru.Add("key1", String("value1"))
+	lru.Get("key1")
+	lru.Get("missing")
+
+	lru.Reset()
+	s := lru.Stats()
+	if s.Hits != 0 || s.Misses != 0 || s.Evictions != 0 {
+		t.Fatalf("Reset should zero counters, got %+v", s)
+	}
+	if s.Entries != 1 {
+		t.Fatalf("Reset should not affect Entries, got %d", s.Entries)
+	}
+}
+
+// 测试 WithStatsSink 在每次移除记录后都会被回调一次
+func TestCache_WithStatsSink(t *testing.T) {
+	calls := 0
+	lru := New(int64(len("key1value1")), nil, WithStatsSink(func(s Stats) {
+		calls++
+	}))
+	lru.Add("key1", String("value1"))
+	lru.Add("key2", String("value2")) // 触发一次淘汰
+
+	if calls != 1 {
+		t.Fatalf("expected statsSink to be called once, got %d", calls)
+	}
+}
@@ -0,0 +1,120 @@
+package lru
+
+import "time"
+
+// Reason 说明一条记录是因为什么原因被移除的，随 OnEvictedFunc 回调传出
+type Reason int
+
+const (
+	ReasonEvicted Reason = iota // 因超出 maxBytes 被 Policy 淘汰
+	ReasonExpired               // TTL 到期，被 Get 发现或被 janitor 主动清理
+	ReasonRemoved               // 被 Remove/Purge 显式删除
+)
+
+// String 实现 fmt.Stringer，方便日志打印
+func (r Reason) String() string {
+	switch r {
+	case ReasonEvicted:
+		return "evicted"
+	case ReasonExpired:
+		return "expired"
+	case ReasonRemoved:
+		return "removed"
+	default:
+		return "unknown"
+	}
+}
+
+// OnEvictedFunc 是新版的移除回调，比旧版多了一个 reason 参数
+type OnEvictedFunc func(key string, value Value, reason Reason)
+
+// LegacyOnEvicted 把旧版只关心 key/value 的回调适配成 OnEvictedFunc，丢弃 reason，
+// 用于兼容 New/NewWithK 的老签名
+func LegacyOnEvicted(f func(key string, value Value)) OnEvictedFunc {
+	if f == nil {
+		return nil
+	}
+	return func(key string, value Value, _ Reason) {
+		f(key, value)
+	}
+}
+
+// expired 判断这条 entry 是否已经过期，expireAt 为零值表示没有设置 TTL
+func (e *entry) expired() bool {
+	return !e.expireAt.IsZero() && time.Now().After(e.expireAt)
+}
+
+// AddWithTTL 新增/修改一个 key，并为其设置过期时间，过期后 Get 会视为未命中并移除该记录。
+// ttl <= 0 等价于不设置过期时间的 Add。
+// 注意：带 TTL 的写入直接进入主缓存，不会经过 LRU-K 的历史队列准入；如果这个 key
+// 当时正停留在历史队列里（尚未被提升），这里会先把它从历史队列中清理掉，
+// 避免同一个 key 同时存在于 items 和 historyCache 里造成 historyBytes 泄漏。
+func (c *Cache) AddWithTTL(key string, value Value, ttl time.Duration) {
+	c.maybeSweep()
+	if ttl <= 0 {
+		c.Add(key, value)
+		return
+	}
+	c.removeFromHistory(key)
+	c.addToMain(key, value, time.Now().Add(ttl))
+}
+
+// janitorSampleSize 是每次惰性清理抽样检查的 key 数量上限，避免一次扫描全表造成停顿
+const janitorSampleSize = 20
+
+// janitor 记录惰性主动过期的检查节奏。这里特意不用独立的后台 goroutine：Cache 本身
+// 没有锁保护 items/policy，后台 goroutine 和调用方的 Get/Add 并发执行会直接打架
+// （data race / concurrent map read and write）。退而求其次的做法是把抽样清理摊派到
+// Get/Add 自身：每次调用先看看据上次清理是否已经过了 interval，是的话就顺带清理一批，
+// 这样清理动作和正常的读写操作发生在同一个 goroutine 里，不需要额外加锁，
+// 近似达到类似 Redis 主动过期的效果——只是触发节奏跟随缓存的访问频率，而不是独立的时钟。
+type janitor struct {
+	interval  time.Duration
+	lastSweep time.Time
+}
+
+// WithJanitor 开启惰性主动过期：之后每次 Get/Add/AddWithTTL 都会检查一次，
+// 如果距上次清理已经超过 interval，就顺带抽样清理一批过期的 key。
+// interval <= 0 时不生效。可以用 Stop 随时关闭。
+func WithJanitor(interval time.Duration) Option {
+	return func(c *Cache) {
+		if interval <= 0 {
+			return
+		}
+		c.janitor = &janitor{interval: interval}
+	}
+}
+
+// maybeSweep 在距离上次清理超过 interval 时抽样清理一批过期的 key，否则什么都不做
+func (c *Cache) maybeSweep() {
+	if c.janitor == nil {
+		return
+	}
+	now := time.Now()
+	if now.Sub(c.janitor.lastSweep) < c.janitor.interval {
+		return
+	}
+	c.janitor.lastSweep = now
+	c.expireSample()
+}
+
+// expireSample 抽样检查一批 key 是否过期，过期的以 ReasonExpired 移除。
+// map 的遍历顺序本身是随机的，这里直接借助它来实现抽样，不需要额外维护顺序。
+func (c *Cache) expireSample() {
+	sampled := 0
+	for key, e := range c.items {
+		if sampled >= janitorSampleSize {
+			return
+		}
+		sampled++
+		if e.expired() {
+			c.policy.Remove(key)
+			c.removeEntry(key, e, ReasonExpired)
+		}
+	}
+}
+
+// Stop 关闭惰性主动过期，幂等，未开启 janitor 时调用无副作用
+func (c *Cache) Stop() {
+	c.janitor = nil
+}
@@ -0,0 +1,83 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+// 测试 TTL：过期的 key 对 Get 而言表现为 miss，并且会从缓存中移除
+func TestCache_AddWithTTL_Expired(t *testing.T) {
+	lru := New(int64(1<<20), nil)
+	lru.AddWithTTL("key1", String("v1"), 10*time.Millisecond)
+
+	if v, ok := lru.Get("key1"); !ok || string(v.(String)) != "v1" {
+		t.Fatalf("key1 should still be valid before ttl expires")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := lru.Get("key1"); ok {
+		t.Fatalf("key1 should have expired")
+	}
+	if lru.Len() != 0 {
+		t.Fatalf("expired key1 should have been removed, cache length: %d", lru.Len())
+	}
+}
+
+// 测试 OnEvicted 回调能区分淘汰和过期这两种移除原因
+func TestCache_OnEvictedReason(t *testing.T) {
+	reasons := make(map[string]Reason)
+	lru := New(int64(1<<20), nil)
+	lru.OnEvicted = func(key string, value Value, reason Reason) {
+		reasons[key] = reason
+	}
+
+	lru.AddWithTTL("expiring", String("v"), 10*time.Millisecond)
+	lru.Add("removed", String("v"))
+
+	time.Sleep(20 * time.Millisecond)
+	lru.Get("expiring") // 触发被动过期清理
+	lru.Remove("removed")
+
+	if reasons["expiring"] != ReasonExpired {
+		t.Fatalf("expiring key should be evicted with ReasonExpired, got %v", reasons["expiring"])
+	}
+	if reasons["removed"] != ReasonRemoved {
+		t.Fatalf("removed key should be evicted with ReasonRemoved, got %v", reasons["removed"])
+	}
+}
+
+// 测试 AddWithTTL 会清理掉同一个 key 尚未提升的历史队列记录，
+// 否则这个 key 会同时留在 items 和 historyCache 里，永久泄漏一份 historyBytes
+func TestCache_AddWithTTL_ClearsPendingHistoryEntry(t *testing.T) {
+	lru := NewWithK(1<<20, 1<<20, 3, nil)
+	lru.Add("k1", String("v0")) // 只写入一次，停留在历史队列里，还没达到 k=3 次被提升
+	if _, ok := lru.historyCache["k1"]; !ok {
+		t.Fatalf("k1 should be sitting in the history queue before promotion")
+	}
+
+	lru.AddWithTTL("k1", String("v1"), time.Hour)
+
+	if _, ok := lru.historyCache["k1"]; ok {
+		t.Fatalf("AddWithTTL should clear k1 out of the history queue")
+	}
+	if e, ok := lru.items["k1"]; !ok || string(e.value.(String)) != "v1" {
+		t.Fatalf("k1 should be written into the main cache by AddWithTTL")
+	}
+}
+
+// 测试惰性主动过期：不需要调用方直接 Get 到已过期的 key，只要访问了其它 key
+// 顺带触发一次抽样清理，过期的 key 也会被清掉
+func TestCache_Janitor_ActiveExpiration(t *testing.T) {
+	lru := New(int64(1<<20), nil, WithJanitor(5*time.Millisecond))
+	defer lru.Stop()
+
+	lru.AddWithTTL("key1", String("v1"), 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	// 访问一个无关的 key，顺带驱动惰性清理；注意这里没有 Get("key1")
+	lru.Add("unrelated", String("v"))
+
+	if lru.Len() != 1 {
+		t.Fatalf("janitor should have actively expired key1, remaining entries: %d", lru.Len())
+	}
+}